@@ -7,12 +7,14 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/mattn/go-colorable"
 	"github.com/suzuki-shunsuke/go-timeout/timeout"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/platform"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
 )
 
 // Apply sends the notification with notifier
@@ -42,15 +44,81 @@ func (ctrl *Controller) Apply(ctx context.Context, command Command) error {
 	uncolorizedStdout := colorable.NewNonColorable(stdout)
 	uncolorizedStderr := colorable.NewNonColorable(stderr)
 	uncolorizedCombinedOutput := colorable.NewNonColorable(combinedOutput)
-	cmd.Stdout = io.MultiWriter(os.Stdout, uncolorizedStdout, uncolorizedCombinedOutput)
+
+	stdoutWriters := []io.Writer{os.Stdout, uncolorizedStdout, uncolorizedCombinedOutput}
+	if hasJSONFlag(command.Args) {
+		stdoutWriters = append(stdoutWriters, newApplyProgressWriter(ctx, ntf))
+	}
+	cmd.Stdout = io.MultiWriter(stdoutWriters...)
 	cmd.Stderr = io.MultiWriter(os.Stderr, uncolorizedStderr, uncolorizedCombinedOutput)
 	_ = timeout.NewRunner(0).Run(ctx, cmd)
 
+	// ctrl.Config.Sensitive holds the raw attribute-name patterns from the
+	// `sensitive:` config section; compile them once per run.
+	sensitive, err := terraform.NewSensitive(ctrl.Config.Sensitive)
+	if err != nil {
+		return apperr.NewExitError(err)
+	}
+
+	body := combinedOutput.String()
+	parser := terraform.NewAutoParser(body)
+	switch p := parser.(type) {
+	case *terraform.PlanParser:
+		p.SetSensitive(sensitive)
+	case *terraform.JSONPlanParser:
+		p.SetSensitive(sensitive)
+	}
+	parsed := parser.Parse(body)
+
 	return apperr.NewExitError(ntf.Apply(ctx, &notifier.ParamExec{
 		Stdout:         stdout.String(),
 		Stderr:         stderr.String(),
-		CombinedOutput: combinedOutput.String(),
+		CombinedOutput: body,
 		CIName:         ctrl.Config.CI.Name,
 		ExitCode:       cmd.ProcessState.ExitCode(),
+		Parsed:         &parsed,
 	}))
 }
+
+func hasJSONFlag(args []string) bool {
+	for _, a := range args {
+		if a == "-json" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyProgressWriter scans `terraform apply -json` output line by line as
+// it arrives and forwards each recognized event to the notifier.
+type applyProgressWriter struct {
+	ctx      context.Context
+	notifier notifier.Notifier
+	buf      bytes.Buffer
+}
+
+func newApplyProgressWriter(ctx context.Context, n notifier.Notifier) *applyProgressWriter {
+	return &applyProgressWriter{ctx: ctx, notifier: n}
+}
+
+func (w *applyProgressWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Incomplete line: keep it buffered for the next Write.
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+
+		event, ok := terraform.ParseApplyJSONLine(strings.TrimRight(line, "\n"))
+		if !ok {
+			continue
+		}
+
+		_ = w.notifier.ApplyProgress(w.ctx, event)
+	}
+
+	return len(p), nil
+}
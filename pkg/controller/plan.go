@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/mattn/go-colorable"
+	"github.com/suzuki-shunsuke/go-timeout/timeout"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/platform"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// Plan sends the notification with notifier
+func (ctrl *Controller) Plan(ctx context.Context, command Command) error {
+	if err := platform.Complement(&ctrl.Config); err != nil {
+		return err
+	}
+
+	if err := ctrl.Config.Validate(); err != nil {
+		return err
+	}
+
+	ntf, err := ctrl.getNotifier(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ntf == nil {
+		return errors.New("no notifier specified at all")
+	}
+
+	cmd := exec.Command(command.Cmd, command.Args...) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	combinedOutput := &bytes.Buffer{}
+	uncolorizedStdout := colorable.NewNonColorable(stdout)
+	uncolorizedStderr := colorable.NewNonColorable(stderr)
+	uncolorizedCombinedOutput := colorable.NewNonColorable(combinedOutput)
+	cmd.Stdout = io.MultiWriter(os.Stdout, uncolorizedStdout, uncolorizedCombinedOutput)
+	cmd.Stderr = io.MultiWriter(os.Stderr, uncolorizedStderr, uncolorizedCombinedOutput)
+	_ = timeout.NewRunner(0).Run(ctx, cmd)
+
+	// ctrl.Config.Sensitive holds the raw attribute-name patterns from the
+	// `sensitive:` config section; compile them once per run.
+	sensitive, err := terraform.NewSensitive(ctrl.Config.Sensitive)
+	if err != nil {
+		return apperr.NewExitError(err)
+	}
+
+	body := combinedOutput.String()
+	parser := terraform.NewAutoParser(body)
+	switch p := parser.(type) {
+	case *terraform.PlanParser:
+		p.SetSensitive(sensitive)
+	case *terraform.JSONPlanParser:
+		p.SetSensitive(sensitive)
+	}
+	result := parser.Parse(body)
+
+	return apperr.NewExitError(ntf.Plan(ctx, &result))
+}
@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// CommentClient is the subset of a PR-comment API the GitHub notifier needs.
+type CommentClient interface {
+	PostComment(ctx context.Context, body string) (commentID int64, err error)
+	EditComment(ctx context.Context, commentID int64, body string) error
+}
+
+// progressDebounceInterval bounds how often ApplyProgress edits the PR
+// comment; terraform can emit many hook events per second for large
+// applies, and editing a GitHub comment that often would get rate limited.
+const progressDebounceInterval = 15 * time.Second
+
+// GitHub is the default Notifier implementation. Plan and Apply each post a
+// new PR comment; during a `-json` apply, ApplyProgress keeps a separate
+// progress comment that it edits in place, and Apply does not reuse it.
+type GitHub struct {
+	Client CommentClient
+
+	mu        sync.Mutex
+	commentID int64
+	started   map[string]time.Time
+	applied   int
+	total     int
+	lastPost  time.Time
+}
+
+// NewGitHub is GitHub initializer
+func NewGitHub(client CommentClient) *GitHub {
+	return &GitHub{Client: client, started: map[string]time.Time{}}
+}
+
+// Plan renders the plan result and posts it as a PR comment.
+func (g *GitHub) Plan(ctx context.Context, result *terraform.ParseResult) error {
+	body := result.Result
+	if sections := FormatResourceSections(result); sections != "" {
+		body = fmt.Sprintf("%s\n\n%s", body, sections)
+	}
+	_, err := g.Client.PostComment(ctx, body)
+	return err
+}
+
+// Apply renders the apply result and posts it as a PR comment.
+func (g *GitHub) Apply(ctx context.Context, param *ParamExec) error {
+	body := param.CombinedOutput
+	if param.Parsed != nil {
+		if sections := FormatResourceSections(param.Parsed); sections != "" {
+			body = fmt.Sprintf("%s\n\n%s", body, sections)
+		}
+	}
+	_, err := g.Client.PostComment(ctx, body)
+	return err
+}
+
+// ApplyProgress coalesces `terraform apply -json` events into a single,
+// repeatedly-edited PR comment instead of posting a new comment per event.
+// Bookkeeping is updated for every event, but the comment itself is only
+// posted/edited at most once per progressDebounceInterval.
+func (g *GitHub) ApplyProgress(ctx context.Context, event terraform.ApplyEvent) error {
+	g.mu.Lock()
+	switch event.Type {
+	case terraform.ApplyEventTypeChangeSummary:
+		g.total = event.Changes.Add + event.Changes.Change + event.Changes.Remove
+	case terraform.ApplyEventTypeApplyStart:
+		g.started[event.Hook.Resource.Addr] = time.Now()
+	case terraform.ApplyEventTypeApplyComplete, terraform.ApplyEventTypeApplyErrored:
+		delete(g.started, event.Hook.Resource.Addr)
+		g.applied++
+	}
+
+	commentID := g.commentID
+	if commentID != 0 && time.Since(g.lastPost) < progressDebounceInterval {
+		g.mu.Unlock()
+		return nil
+	}
+	body := g.renderProgressLocked()
+	g.lastPost = time.Now()
+	g.mu.Unlock()
+
+	if commentID == 0 {
+		id, err := g.Client.PostComment(ctx, body)
+		if err != nil {
+			return err
+		}
+		g.mu.Lock()
+		g.commentID = id
+		g.mu.Unlock()
+		return nil
+	}
+
+	return g.Client.EditComment(ctx, commentID, body)
+}
+
+// renderProgressLocked builds the "N/M resources applied, K in progress:
+// ..." status line. Callers must hold g.mu.
+func (g *GitHub) renderProgressLocked() string {
+	inProgress := make([]string, 0, len(g.started))
+	for addr, startedAt := range g.started {
+		inProgress = append(inProgress, fmt.Sprintf("%s (%ds)", addr, int(time.Since(startedAt).Seconds())))
+	}
+	sort.Strings(inProgress)
+
+	status := fmt.Sprintf("%d/%d resources applied", g.applied, g.total)
+	if len(inProgress) > 0 {
+		status = fmt.Sprintf("%s, %d in progress: %s", status, len(inProgress), strings.Join(inProgress, ", "))
+	}
+	return status
+}
@@ -0,0 +1,41 @@
+// Package notifier posts terraform plan/apply results to a pull request.
+package notifier
+
+import (
+	"context"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// ParamExec holds the result of running a terraform command, used by
+// Notifier.Apply to render a PR comment.
+type ParamExec struct {
+	Stdout         string
+	Stderr         string
+	CombinedOutput string
+	CIName         string
+	ExitCode       int
+	// Parsed is the structured result of feeding CombinedOutput through
+	// terraform.NewAutoParser, so notifiers don't each need to re-detect and
+	// re-parse raw terraform output themselves.
+	Parsed *terraform.ParseResult
+}
+
+// Notifier posts terraform plan/apply results to a PR.
+type Notifier interface {
+	Plan(ctx context.Context, result *terraform.ParseResult) error
+	Apply(ctx context.Context, param *ParamExec) error
+	// ApplyProgress is called with each recognized `terraform apply -json`
+	// event as it streams in, so a notifier can render live progress.
+	ApplyProgress(ctx context.Context, event terraform.ApplyEvent) error
+}
+
+// NoopProgress is embedded by notifiers that have no incremental progress
+// UI (anything but the GitHub notifier today), so they satisfy Notifier's
+// ApplyProgress method for free.
+type NoopProgress struct{}
+
+// ApplyProgress discards the event.
+func (NoopProgress) ApplyProgress(context.Context, terraform.ApplyEvent) error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// FormatResourceSections renders the moved/imported/removed resources from
+// result as distinct, labelled sections so the PR comment doesn't lump a
+// move or an import in with plain creates/destroys. It returns "" when none
+// of those three fields are populated.
+func FormatResourceSections(result *terraform.ParseResult) string {
+	var sections []string
+
+	if len(result.MovedResources) > 0 {
+		sections = append(sections, formatSection("Moved", result.MovedResources))
+	}
+	if len(result.ImportedResources) > 0 {
+		sections = append(sections, formatSection("Imported (with generated config)", result.ImportedResources))
+	}
+	if len(result.ForgottenResources) > 0 {
+		sections = append(sections, formatSection("Removed from state (not destroyed)", result.ForgottenResources))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func formatSection(title string, resources []string) string {
+	lines := make([]string, 0, len(resources)+1)
+	lines = append(lines, fmt.Sprintf("%s:", title))
+	for _, r := range resources {
+		lines = append(lines, fmt.Sprintf("- %s", r))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+package terraform
+
+import "encoding/json"
+
+// Apply event types emitted by `terraform apply -json` as the value of the
+// top-level `type` field.
+const (
+	ApplyEventTypeApplyStart    = "apply_start"
+	ApplyEventTypeApplyProgress = "apply_progress"
+	ApplyEventTypeApplyComplete = "apply_complete"
+	ApplyEventTypeApplyErrored  = "apply_errored"
+	ApplyEventTypeChangeSummary = "change_summary"
+)
+
+// ApplyEvent is a single line of the `terraform apply -json` log stream,
+// decoded just enough to drive incremental progress reporting.
+type ApplyEvent struct {
+	Level   string `json:"@level"`
+	Message string `json:"@message"`
+	Type    string `json:"type"`
+	Hook    struct {
+		Resource struct {
+			Addr string `json:"addr"`
+		} `json:"resource"`
+		Action      string  `json:"action"`
+		ElapsedSecs float64 `json:"elapsed_seconds"`
+	} `json:"hook"`
+	Changes struct {
+		Add       int    `json:"add"`
+		Change    int    `json:"change"`
+		Remove    int    `json:"remove"`
+		Operation string `json:"operation"`
+	} `json:"changes"`
+}
+
+// ParseApplyJSONLine decodes a single line of `terraform apply -json`
+// output. It returns ok=false if the line is not a recognized apply event,
+// so callers can skip blank lines or stray non-JSON output without erroring
+// the whole stream.
+func ParseApplyJSONLine(line string) (ApplyEvent, bool) {
+	var event ApplyEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return ApplyEvent{}, false
+	}
+
+	switch event.Type {
+	case ApplyEventTypeApplyStart, ApplyEventTypeApplyProgress, ApplyEventTypeApplyComplete,
+		ApplyEventTypeApplyErrored, ApplyEventTypeChangeSummary:
+		return event, true
+	default:
+		return ApplyEvent{}, false
+	}
+}
@@ -0,0 +1,334 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSONPlanParser is a parser for the structured JSON output produced by
+// `terraform plan -json` / `terraform apply -json` (a line-delimited stream
+// of UI messages) as well as the single-document `plan.json` produced by
+// `terraform show -json <planfile>`.
+type JSONPlanParser struct {
+	Sensitive *Sensitive
+}
+
+// NewJSONPlanParser is JSONPlanParser initializer
+func NewJSONPlanParser() *JSONPlanParser {
+	return &JSONPlanParser{}
+}
+
+// SetSensitive configures the attribute-name patterns from the `sensitive:`
+// config section that JSONPlanParser should mask in addition to the values
+// Terraform itself already marked as sensitive.
+func (p *JSONPlanParser) SetSensitive(sensitive *Sensitive) {
+	p.Sensitive = sensitive
+}
+
+// uiMessage is a single line of the `terraform plan -json` / `terraform
+// apply -json` log stream.
+type uiMessage struct {
+	Level      string `json:"@level"`
+	Message    string `json:"@message"`
+	Type       string `json:"type"`
+	Diagnostic *struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail"`
+		Range    *struct {
+			Filename string `json:"filename"`
+		} `json:"range"`
+	} `json:"diagnostic"`
+	Change *struct {
+		Resource struct {
+			Addr string `json:"addr"`
+		} `json:"resource"`
+		PreviousResource *struct {
+			Addr string `json:"addr"`
+		} `json:"previous_resource"`
+		Action    string `json:"action"`
+		Importing *struct {
+			ID string `json:"id"`
+		} `json:"importing"`
+	} `json:"change"`
+}
+
+// resourceChange mirrors an entry of `resource_changes` in the
+// `terraform show -json` plan document.
+type resourceChange struct {
+	Address         string `json:"address"`
+	PreviousAddress string `json:"previous_address"`
+	Change          struct {
+		Actions         []string        `json:"actions"`
+		Before          json.RawMessage `json:"before"`
+		After           json.RawMessage `json:"after"`
+		BeforeSensitive json.RawMessage `json:"before_sensitive"`
+		AfterSensitive  json.RawMessage `json:"after_sensitive"`
+		Importing       *struct {
+			ID string `json:"id"`
+		} `json:"importing"`
+	} `json:"change"`
+}
+
+// planDocument mirrors the single-document output of
+// `terraform show -json <planfile>`.
+type planDocument struct {
+	FormatVersion   string           `json:"format_version"`
+	ResourceChanges []resourceChange `json:"resource_changes"`
+	ResourceDrift   []resourceChange `json:"resource_drift"`
+}
+
+// LooksLikeJSONPlan sniffs the first non-empty line of stdout to decide
+// whether it is `-json` output (either a line-delimited UI message or a
+// `terraform show -json` document) rather than human-readable text.
+func LooksLikeJSONPlan(body string) bool {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "{") {
+			return false
+		}
+		var probe struct {
+			Message         string            `json:"@message"`
+			Type            string            `json:"type"`
+			FormatVersion   string            `json:"format_version"`
+			ResourceChanges []json.RawMessage `json:"resource_changes"`
+		}
+		if err := json.Unmarshal([]byte(line), &probe); err != nil {
+			return false
+		}
+		return probe.Message != "" || probe.Type != "" || probe.FormatVersion != "" || probe.ResourceChanges != nil
+	}
+	return false
+}
+
+// NewAutoParser returns a JSONPlanParser if body looks like `-json` output,
+// falling back to the text-based PlanParser otherwise.
+func NewAutoParser(body string) Parser {
+	if LooksLikeJSONPlan(body) {
+		return NewJSONPlanParser()
+	}
+	return NewPlanParser()
+}
+
+// Parse returns ParseResult related with terraform plan, reading either the
+// line-delimited `-json` log stream or a `terraform show -json` document.
+func (p *JSONPlanParser) Parse(body string) ParseResult {
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "{") && looksLikeSingleDocument(trimmed) {
+		return p.parseDocument(trimmed)
+	}
+	return p.parseStream(body)
+}
+
+func looksLikeSingleDocument(body string) bool {
+	var doc planDocument
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return false
+	}
+	return doc.FormatVersion != ""
+}
+
+func (p *JSONPlanParser) parseDocument(body string) ParseResult {
+	var doc planDocument
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return ParseResult{
+			HasParseError: true,
+			ExitCode:      ExitFail,
+			Error:         fmt.Errorf("parse terraform show -json plan document: %w", err),
+		}
+	}
+
+	result := newActionAggregator()
+	var changeBlocks []string
+	for _, rc := range doc.ResourceChanges {
+		if isNoopChange(rc.Change.Actions) {
+			// Every resource in the configuration gets a resource_changes
+			// entry, most with actions ["no-op"]; only changed resources
+			// belong in the rendered diff.
+			continue
+		}
+
+		result.addChange(rc.Address, rc.Change.Actions, rc.PreviousAddress, rc.Change.Importing != nil)
+
+		value, sensitiveTree := rc.Change.After, rc.Change.AfterSensitive
+		if len(value) == 0 || string(value) == "null" {
+			value, sensitiveTree = rc.Change.Before, rc.Change.BeforeSensitive
+		}
+		block, redacted := renderResourceChange(rc.Address, value, sensitiveTree, p.Sensitive)
+		changeBlocks = append(changeBlocks, block)
+		result.redactedCount += redacted
+	}
+
+	var outsideBlocks []string
+	for _, rc := range doc.ResourceDrift {
+		if isNoopChange(rc.Change.Actions) {
+			continue
+		}
+
+		value, sensitiveTree := rc.Change.After, rc.Change.AfterSensitive
+		if len(value) == 0 || string(value) == "null" {
+			value, sensitiveTree = rc.Change.Before, rc.Change.BeforeSensitive
+		}
+		block, redacted := renderResourceChange(rc.Address, value, sensitiveTree, p.Sensitive)
+		outsideBlocks = append(outsideBlocks, block)
+		result.redactedCount += redacted
+	}
+
+	return result.toParseResult(strings.Join(outsideBlocks, "\n\n"), "", strings.Join(changeBlocks, "\n\n"))
+}
+
+func (p *JSONPlanParser) parseStream(body string) ParseResult {
+	result := newActionAggregator()
+	var outside, warnings, errs []string
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var msg uiMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			continue
+		}
+
+		switch {
+		case msg.Change != nil && msg.Type == "resource_drift":
+			// resource_drift shares its change{resource,action} shape with
+			// planned_change, but it describes drift Terraform noticed
+			// outside of this plan, not a change this plan will make.
+			outside = append(outside, msg.Change.Resource.Addr)
+		case msg.Change != nil:
+			previousAddress := ""
+			if msg.Change.PreviousResource != nil {
+				previousAddress = msg.Change.PreviousResource.Addr
+			}
+			result.addChange(msg.Change.Resource.Addr, []string{msg.Change.Action}, previousAddress, msg.Change.Importing != nil)
+		case msg.Diagnostic != nil:
+			detail := fmt.Sprintf("%s: %s\n%s", msg.Diagnostic.Severity, msg.Diagnostic.Summary, msg.Diagnostic.Detail)
+			switch msg.Level {
+			case "warn":
+				warnings = append(warnings, detail)
+			case "error":
+				errs = append(errs, detail)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return ParseResult{
+			Result:       strings.Join(errs, "\n"),
+			HasPlanError: true,
+			ExitCode:     ExitFail,
+			Error:        nil,
+		}
+	}
+
+	return result.toParseResult(strings.Join(outside, "\n"), strings.Join(warnings, "\n"), "")
+}
+
+// actionAggregator accumulates per-resource terraform actions and derives
+// the same summary fields that PlanParser produces from text output.
+type actionAggregator struct {
+	created       []string
+	updated       []string
+	deleted       []string
+	replaced      []string
+	moved         []string
+	imported      []string
+	forgotten     []string
+	redactedCount int
+}
+
+func newActionAggregator() *actionAggregator {
+	return &actionAggregator{}
+}
+
+// addChange records a single resource_changes/planned_change entry.
+func (a *actionAggregator) addChange(addr string, actions []string, previousAddress string, importing bool) {
+	if previousAddress != "" && previousAddress != addr {
+		a.moved = append(a.moved, fmt.Sprintf("%s has moved to %s", previousAddress, addr))
+	}
+
+	switch {
+	case contains(actions, "forget"):
+		a.forgotten = append(a.forgotten, addr)
+	case containsAll(actions, "create", "delete"), contains(actions, "replace"):
+		// The line-delimited stream reports a replacement as the single
+		// action "replace"; the show -json document reports it as the pair
+		// ["create","delete"].
+		a.replaced = append(a.replaced, addr)
+	case contains(actions, "create"):
+		if importing {
+			// An import with generated config shouldn't also count as a
+			// normal create.
+			a.imported = append(a.imported, addr)
+			return
+		}
+		a.created = append(a.created, addr)
+	case contains(actions, "update"):
+		a.updated = append(a.updated, addr)
+	case contains(actions, "delete"):
+		a.deleted = append(a.deleted, addr)
+	}
+}
+
+// isNoopChange reports whether actions describes a resource with nothing to
+// do: "no-op" (unchanged) or a read-only data source refresh.
+func isNoopChange(actions []string) bool {
+	return contains(actions, "no-op") || contains(actions, "read")
+}
+
+func contains(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(actions []string, wanted ...string) bool {
+	for _, w := range wanted {
+		if !contains(actions, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *actionAggregator) toParseResult(outsideTerraform, warning, changeResult string) ParseResult {
+	hasDestroy := len(a.deleted) > 0 || len(a.replaced) > 0
+	hasAddOrUpdateOnly := !hasDestroy && (len(a.created) > 0 || len(a.updated) > 0)
+	hasAnyChange := hasDestroy || hasAddOrUpdateOnly || len(a.moved) > 0 || len(a.imported) > 0 || len(a.forgotten) > 0
+	hasNoChanges := !hasAnyChange
+
+	result := fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.",
+		len(a.created), len(a.updated), len(a.deleted)+len(a.replaced))
+	if hasNoChanges {
+		result = NoChanges
+	}
+
+	return ParseResult{
+		Result:             result,
+		ChangedResult:      changeResult,
+		OutsideTerraform:   outsideTerraform,
+		Warning:            warning,
+		HasAddOrUpdateOnly: hasAddOrUpdateOnly,
+		HasDestroy:         hasDestroy,
+		HasNoChanges:       hasNoChanges,
+		ExitCode:           ExitPass,
+		CreatedResources:   a.created,
+		UpdatedResources:   a.updated,
+		DeletedResources:   a.deleted,
+		ReplacedResources:  a.replaced,
+		MovedResources:     a.moved,
+		ImportedResources:  a.imported,
+		ForgottenResources: a.forgotten,
+		RedactedCount:      a.redactedCount,
+	}
+}
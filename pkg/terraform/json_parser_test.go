@@ -0,0 +1,249 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLooksLikeJSONPlan(t *testing.T) {
+	stream := `{"@level":"info","@message":"aws_instance.foo will be created","type":"planned_change","change":{"resource":{"addr":"aws_instance.foo"},"action":"create"}}
+`
+	if !LooksLikeJSONPlan(stream) {
+		t.Error("expected a line-delimited -json stream to be detected as JSON")
+	}
+
+	doc := `{"format_version": "1.2", "resource_changes": []}`
+	if !LooksLikeJSONPlan(doc) {
+		t.Error("expected a terraform show -json document to be detected as JSON")
+	}
+
+	if LooksLikeJSONPlan("Terraform will perform the following actions:\n") {
+		t.Error("expected human-readable plan output not to be detected as JSON")
+	}
+}
+
+func TestJSONPlanParserParseStream(t *testing.T) {
+	body := `{"@level":"info","@message":"Terraform will perform the following actions:","type":"resource_drift","change":{"resource":{"addr":"aws_instance.drifted"},"action":"update"}}
+{"@level":"info","@message":"aws_instance.foo will be created","type":"planned_change","change":{"resource":{"addr":"aws_instance.foo"},"action":"create"}}
+{"@level":"info","@message":"aws_instance.bar will be destroyed","type":"planned_change","change":{"resource":{"addr":"aws_instance.bar"},"action":"delete"}}
+{"@level":"warn","@message":"deprecated","type":"diagnostic","diagnostic":{"severity":"warning","summary":"deprecated attribute","detail":"use new_attr instead"}}
+`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if got, want := len(result.CreatedResources), 1; got != want {
+		t.Errorf("CreatedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.DeletedResources), 1; got != want {
+		t.Errorf("DeletedResources = %d, want %d", got, want)
+	}
+	if result.OutsideTerraform != "aws_instance.drifted" {
+		t.Errorf("OutsideTerraform = %q, want the resource_drift entry, not a planned change", result.OutsideTerraform)
+	}
+	if result.Warning == "" {
+		t.Error("Warning should not be empty")
+	}
+	if !result.HasDestroy {
+		t.Error("HasDestroy should be true")
+	}
+}
+
+func TestJSONPlanParserParseStreamReplace(t *testing.T) {
+	// The line-delimited stream reports a replacement as the single action
+	// "replace", unlike the show -json document's ["create","delete"] pair.
+	body := `{"@level":"info","@message":"aws_instance.foo must be replaced","type":"planned_change","change":{"resource":{"addr":"aws_instance.foo"},"action":"replace"}}
+`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if got, want := len(result.ReplacedResources), 1; got != want {
+		t.Errorf("ReplacedResources = %d, want %d", got, want)
+	}
+	if !result.HasDestroy {
+		t.Error("HasDestroy should be true for a replacement")
+	}
+}
+
+func TestJSONPlanParserImportDoesNotDoubleCountAsCreate(t *testing.T) {
+	body := `{
+		"format_version": "1.2",
+		"resource_changes": [
+			{
+				"address": "aws_instance.foo",
+				"change": {"actions": ["create"], "importing": {"id": "i-123"}}
+			}
+		]
+	}`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if got, want := len(result.ImportedResources), 1; got != want {
+		t.Errorf("ImportedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.CreatedResources), 0; got != want {
+		t.Errorf("CreatedResources = %d, want %d (import should not also count as a create)", got, want)
+	}
+}
+
+func TestJSONPlanParserParseStreamMovedAndImported(t *testing.T) {
+	body := `{"@level":"info","@message":"aws_instance.bar has moved to aws_instance.foo","type":"planned_change","change":{"resource":{"addr":"aws_instance.foo"},"previous_resource":{"addr":"aws_instance.bar"},"action":"update"}}
+{"@level":"info","@message":"aws_instance.baz will be imported","type":"planned_change","change":{"resource":{"addr":"aws_instance.baz"},"action":"create","importing":{"id":"i-123"}}}
+`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if got, want := len(result.MovedResources), 1; got != want {
+		t.Errorf("MovedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.ImportedResources), 1; got != want {
+		t.Errorf("ImportedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.CreatedResources), 0; got != want {
+		t.Errorf("CreatedResources = %d, want %d (import should not also count as a create)", got, want)
+	}
+}
+
+func TestJSONPlanParserForgottenOnlyIsNotNoChanges(t *testing.T) {
+	body := `{
+		"format_version": "1.2",
+		"resource_changes": [
+			{
+				"address": "aws_instance.foo",
+				"change": {"actions": ["forget"]}
+			}
+		]
+	}`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if result.HasNoChanges {
+		t.Error("HasNoChanges should be false when a resource is being forgotten")
+	}
+	if got, want := len(result.ForgottenResources), 1; got != want {
+		t.Errorf("ForgottenResources = %d, want %d", got, want)
+	}
+}
+
+func TestJSONPlanParserParsePartialStream(t *testing.T) {
+	// A stream that ends mid-apply (e.g. the apply failed partway through)
+	// should still parse the complete lines it did get.
+	body := `{"@level":"info","@message":"aws_instance.foo will be created","type":"planned_change","change":{"resource":{"addr":"aws_instance.foo"},"action":"create"}}
+{"@level":"error","@message":"failed","type":"diagnostic","diagnostic":{"severity":"error","summary":"apply failed","detail":"connection refused"}}
+`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if !result.HasPlanError {
+		t.Error("HasPlanError should be true when a diagnostic error is present")
+	}
+	if result.Result == "" {
+		t.Error("Result should contain the rendered error")
+	}
+}
+
+func TestJSONPlanParserParseDocument(t *testing.T) {
+	body := `{
+		"format_version": "1.2",
+		"resource_changes": [
+			{
+				"address": "aws_instance.foo",
+				"change": {"actions": ["create"]}
+			},
+			{
+				"address": "aws_instance.bar",
+				"change": {"actions": ["update"]}
+			}
+		]
+	}`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if got, want := len(result.CreatedResources), 1; got != want {
+		t.Errorf("CreatedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.UpdatedResources), 1; got != want {
+		t.Errorf("UpdatedResources = %d, want %d", got, want)
+	}
+}
+
+func TestJSONPlanParserParseDocumentSkipsNoop(t *testing.T) {
+	body := `{
+		"format_version": "1.2",
+		"resource_changes": [
+			{
+				"address": "aws_instance.foo",
+				"change": {"actions": ["create"]}
+			},
+			{
+				"address": "aws_instance.unchanged",
+				"change": {"actions": ["no-op"], "after": {"ami": "ami-123"}}
+			},
+			{
+				"address": "data.aws_ami.unchanged",
+				"change": {"actions": ["read"], "after": {"ami": "ami-123"}}
+			}
+		]
+	}`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if got, want := len(result.CreatedResources), 1; got != want {
+		t.Errorf("CreatedResources = %d, want %d", got, want)
+	}
+	if strings.Contains(result.ChangedResult, "unchanged") {
+		t.Errorf("ChangedResult = %q, want no-op/read-only resources to be skipped", result.ChangedResult)
+	}
+}
+
+func TestJSONPlanParserParseDocumentRedactsSensitiveValues(t *testing.T) {
+	body := `{
+		"format_version": "1.2",
+		"resource_changes": [
+			{
+				"address": "aws_instance.foo",
+				"change": {
+					"actions": ["create"],
+					"after": {"ami": "ami-123", "password": "hunter2"},
+					"after_sensitive": {"password": true}
+				}
+			}
+		]
+	}`
+
+	result := NewJSONPlanParser().Parse(body)
+
+	if result.RedactedCount != 1 {
+		t.Errorf("RedactedCount = %d, want 1", result.RedactedCount)
+	}
+	if !strings.Contains(result.ChangedResult, `"***"`) {
+		t.Errorf("ChangedResult = %q, want it to mask the sensitive password value", result.ChangedResult)
+	}
+	if strings.Contains(result.ChangedResult, "hunter2") {
+		t.Errorf("ChangedResult leaked the sensitive value: %q", result.ChangedResult)
+	}
+	if !strings.Contains(result.ChangedResult, "ami-123") {
+		t.Errorf("ChangedResult = %q, want non-sensitive attributes to still be rendered", result.ChangedResult)
+	}
+}
+
+func TestRedactChangeResultSkipsKnownAfterApply(t *testing.T) {
+	body := "      id = (known after apply)\n      password = (sensitive value)"
+
+	redacted, count := redactChangeResult(body, nil)
+
+	if count != 1 {
+		t.Errorf("count = %d, want 1 (only the sensitive value marker should count)", count)
+	}
+	if !strings.Contains(redacted, "(known after apply)") {
+		t.Errorf("redacted = %q, want the known after apply marker left untouched", redacted)
+	}
+}
+
+func TestNewAutoParser(t *testing.T) {
+	if _, ok := NewAutoParser(`{"format_version": "1.2", "resource_changes": []}`).(*JSONPlanParser); !ok {
+		t.Error("expected NewAutoParser to return a JSONPlanParser for -json output")
+	}
+	if _, ok := NewAutoParser("Terraform will perform the following actions:\n").(*PlanParser); !ok {
+		t.Error("expected NewAutoParser to fall back to PlanParser for text output")
+	}
+}
@@ -30,6 +30,10 @@ type ParseResult struct {
 	UpdatedResources   []string
 	DeletedResources   []string
 	ReplacedResources  []string
+	MovedResources     []string
+	ImportedResources  []string
+	ForgottenResources []string
+	RedactedCount      int
 }
 
 // DefaultParser is a parser for terraform commands
@@ -46,6 +50,10 @@ type PlanParser struct {
 	Delete        *regexp.Regexp
 	Replace       *regexp.Regexp
 	ReplaceOption *regexp.Regexp
+	Moved         *regexp.Regexp
+	Removed       *regexp.Regexp
+	Imported      *regexp.Regexp
+	Sensitive     *Sensitive
 }
 
 // ApplyParser is a parser for terraform apply
@@ -67,14 +75,27 @@ func NewPlanParser() *PlanParser {
 		// "0 to destroy" should be treated as "no destroy"
 		HasDestroy:    regexp.MustCompile(`(?m)([1-9][0-9]* to destroy.)`),
 		HasNoChanges:  regexp.MustCompile(`(?m)^(No changes.)`),
-		Create:        regexp.MustCompile(`^ *# (.*) will be created$`),
-		Update:        regexp.MustCompile(`^ *# (.*) will be updated in-place$`),
-		Delete:        regexp.MustCompile(`^ *# (.*) will be destroyed$`),
-		Replace:       regexp.MustCompile(`^ *# (.*?)(?: is tainted, so)? must be replaced$`),
-		ReplaceOption: regexp.MustCompile(`^ *# (.*?) will be replaced, as requested$`),
+		Create:        regexp.MustCompile(`(?m)^ *# (.*) will be created$`),
+		Update:        regexp.MustCompile(`(?m)^ *# (.*) will be updated in-place$`),
+		Delete:        regexp.MustCompile(`(?m)^ *# (.*) will be destroyed$`),
+		Replace:       regexp.MustCompile(`(?m)^ *# (.*?)(?: is tainted, so)? must be replaced$`),
+		ReplaceOption: regexp.MustCompile(`(?m)^ *# (.*?) will be replaced, as requested$`),
+		// "moved" blocks (Terraform 1.1+): `# aws_instance.foo has moved to aws_instance.bar`
+		Moved: regexp.MustCompile(`(?m)^ *# (.*) has moved to (.*)$`),
+		// "removed" blocks (Terraform 1.7+): the resource is dropped from state without destroying it
+		Removed: regexp.MustCompile(`(?m)^ *# (.*) will no longer be managed by Terraform$`),
+		// "import" blocks / generated config (Terraform 1.5+)
+		Imported: regexp.MustCompile(`(?m)^ *# (.*) will be imported$`),
 	}
 }
 
+// SetSensitive configures the attribute-name patterns from the `sensitive:`
+// config section that PlanParser should mask in addition to the values
+// Terraform itself already marked as sensitive.
+func (p *PlanParser) SetSensitive(sensitive *Sensitive) {
+	p.Sensitive = sensitive
+}
+
 // NewApplyParser is ApplyParser initialized with its Regexp
 func NewApplyParser() *ApplyParser {
 	return &ApplyParser{
@@ -128,6 +149,9 @@ func (p *PlanParser) Parse(body string) ParseResult { //noli nt:cyclop
 	replacedResources := extractAllResources(p.Replace, body)
 	replacedOptResources := extractAllResources(p.ReplaceOption, body)
 	replacedResources = append(replacedResources, replacedOptResources...)
+	movedResources := extractAllResources(p.Moved, body)
+	importedResources := extractAllResources(p.Imported, body)
+	forgottenResources := extractAllResources(p.Removed, body)
 
 	var result string
 	var hasDestroy, hasNoChanges, hasAddOrUpdateOnly bool
@@ -141,14 +165,20 @@ func (p *PlanParser) Parse(body string) ParseResult { //noli nt:cyclop
 		hasAddOrUpdateOnly = (resultHasChanges(result) || resultHasAdds(result)) && !resultHasDestroys(result)
 	}
 
+	var redactedCount int
+
 	outsideTerraform := ""
 	if len(outsideBlocks) > 0 {
-		outsideTerraform = strings.Join(outsideBlocks, "\n")
+		var redactedOutside int
+		outsideTerraform, redactedOutside = redactChangeResult(strings.Join(outsideBlocks, "\n"), p.Sensitive)
+		redactedCount += redactedOutside
 	}
 
 	changeResult := ""
 	if len(changeBlocks) > 0 {
-		changeResult = strings.Join(changeBlocks, "\n")
+		var redactedChange int
+		changeResult, redactedChange = redactChangeResult(strings.Join(changeBlocks, "\n"), p.Sensitive)
+		redactedCount += redactedChange
 		changeResult = fmt.Sprintf("%s\n\n%s", changeResult, result)
 	}
 
@@ -172,6 +202,10 @@ func (p *PlanParser) Parse(body string) ParseResult { //noli nt:cyclop
 		UpdatedResources:   updatedResources,
 		DeletedResources:   deletedResources,
 		ReplacedResources:  replacedResources,
+		MovedResources:     movedResources,
+		ImportedResources:  importedResources,
+		ForgottenResources: forgottenResources,
+		RedactedCount:      redactedCount,
 	}
 }
 
@@ -210,6 +244,12 @@ func processOutsideBlocksInBody(bodyLines []string) []string {
 	return processBlocksInBody(bodyLines, 0, StartOfOutsideBlock, EndOfOutsideBlock)
 }
 
+// processChangeBlocksInBody extracts the "Terraform will perform the
+// following actions:" section. In newer Terraform versions this section can
+// also contain moved/removed/import subsections ahead of the usual
+// create/update/destroy entries; they share the same start/end markers, so
+// they stay in the returned block. Parse lists them separately by matching
+// Moved/Removed/Imported against the whole body.
 func processChangeBlocksInBody(bodyLines []string) []string {
 	return processBlocksInBody(bodyLines, 0, StartOfChangeBlock, EndOfChangeBlock)
 }
@@ -0,0 +1,81 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanParserParse(t *testing.T) {
+	body := `Terraform will perform the following actions:
+
+  # aws_instance.foo will be created
+  + resource "aws_instance" "foo" {
+      + ami      = "ami-123"
+      + password = "hunter2"
+    }
+
+  # aws_instance.bar has moved to aws_instance.baz
+
+  # aws_instance.qux will be imported
+
+  # aws_instance.old will no longer be managed by Terraform
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+`
+
+	result := NewPlanParser().Parse(body)
+
+	if got, want := len(result.CreatedResources), 1; got != want {
+		t.Errorf("CreatedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.MovedResources), 1; got != want {
+		t.Errorf("MovedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.ImportedResources), 1; got != want {
+		t.Errorf("ImportedResources = %d, want %d", got, want)
+	}
+	if got, want := len(result.ForgottenResources), 1; got != want {
+		t.Errorf("ForgottenResources = %d, want %d", got, want)
+	}
+	if result.HasDestroy {
+		t.Error("HasDestroy should be false")
+	}
+	if result.HasNoChanges {
+		t.Error("HasNoChanges should be false")
+	}
+}
+
+func TestPlanParserParseRedactsSensitiveValues(t *testing.T) {
+	body := `Terraform will perform the following actions:
+
+  # aws_instance.foo will be created
+  + resource "aws_instance" "foo" {
+      + ami      = "ami-123"
+      + password = "hunter2"
+    }
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+`
+
+	sensitive, err := NewSensitive([]string{"password"})
+	if err != nil {
+		t.Fatalf("NewSensitive: %v", err)
+	}
+
+	parser := NewPlanParser()
+	parser.SetSensitive(sensitive)
+	result := parser.Parse(body)
+
+	if result.RedactedCount != 1 {
+		t.Errorf("RedactedCount = %d, want 1", result.RedactedCount)
+	}
+	if !strings.Contains(result.ChangedResult, `"***"`) {
+		t.Errorf("ChangedResult = %q, want it to mask the password value", result.ChangedResult)
+	}
+	if strings.Contains(result.ChangedResult, "hunter2") {
+		t.Errorf("ChangedResult leaked the sensitive value: %q", result.ChangedResult)
+	}
+	if !strings.Contains(result.ChangedResult, "ami-123") {
+		t.Errorf("ChangedResult = %q, want non-sensitive attributes to still be rendered", result.ChangedResult)
+	}
+}
@@ -0,0 +1,146 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Sensitive holds compiled `sensitive:` config patterns (a single `*`
+// wildcard is supported, e.g. "password", "*_key").
+type Sensitive struct {
+	AttributePatterns []*regexp.Regexp
+}
+
+// NewSensitive compiles the user-supplied attribute-name patterns.
+func NewSensitive(patterns []string) (*Sensitive, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `.*`) + "$")
+		if err != nil {
+			return nil, fmt.Errorf("compile sensitive attribute pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &Sensitive{AttributePatterns: compiled}, nil
+}
+
+func (s *Sensitive) matches(attr string) bool {
+	if s == nil {
+		return false
+	}
+	for _, re := range s.AttributePatterns {
+		if re.MatchString(attr) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	sensitiveValueMarker  = regexp.MustCompile(`\(sensitive value\)`)
+	knownAfterApplyMarker = regexp.MustCompile(`\(known after apply\)`)
+	attrAssignmentLine    = regexp.MustCompile(`^(\s*[+\-~]?\s*)([A-Za-z0-9_\-."\[\]%]+)(\s*=\s*)(.*)$`)
+	// sensitiveMarkValue finds a true value anywhere in a JSON-encoded
+	// before_sensitive/after_sensitive subtree.
+	sensitiveMarkValue = regexp.MustCompile(`(^|[:\[,])true\b`)
+)
+
+// redactChangeResult masks attribute values in a rendered change block: it
+// counts values Terraform already replaced with "(sensitive value)", skips
+// "(known after apply)" placeholders, and masks any attribute whose name
+// matches one of sensitive's patterns.
+func redactChangeResult(body string, sensitive *Sensitive) (string, int) {
+	if body == "" {
+		return body, 0
+	}
+
+	lines := strings.Split(body, "\n")
+	count := 0
+	for i, line := range lines {
+		switch {
+		case sensitiveValueMarker.MatchString(line):
+			count++
+			continue
+		case knownAfterApplyMarker.MatchString(line):
+			continue
+		}
+
+		m := attrAssignmentLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		attr := strings.Trim(m[2], `"`)
+		if sensitive.matches(attr) {
+			lines[i] = m[1] + m[2] + m[3] + `"***"`
+			count++
+		}
+	}
+
+	return strings.Join(lines, "\n"), count
+}
+
+// renderResourceChange renders a single `resource_changes`/`resource_drift`
+// entry as a diff block, masking attributes Terraform marked sensitive (via
+// sensitiveTree) or that match one of sensitive's patterns.
+func renderResourceChange(address string, value, sensitiveTree json.RawMessage, sensitive *Sensitive) (string, int) {
+	var data, mark interface{}
+	if len(value) != 0 {
+		_ = json.Unmarshal(value, &data)
+	}
+	if len(sensitiveTree) != 0 {
+		_ = json.Unmarshal(sensitiveTree, &mark)
+	}
+
+	lines, count := renderAttributes(data, mark, sensitive)
+	header := fmt.Sprintf("  # %s", address)
+	return strings.Join(append([]string{header}, lines...), "\n"), count
+}
+
+// renderAttributes renders the top-level attributes of a resource's
+// before/after value. A top-level attribute is masked whole when its name
+// matches one of sensitive's patterns or when mark flags it, or any value
+// nested in it, as sensitive.
+func renderAttributes(value, mark interface{}, sensitive *Sensitive) ([]string, int) {
+	attrs, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, 0
+	}
+	marks, _ := mark.(map[string]interface{})
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	count := 0
+	for _, k := range keys {
+		isSensitive := sensitive.matches(k)
+		if b, err := json.Marshal(marks[k]); err == nil && sensitiveMarkValue.Match(b) {
+			isSensitive = true
+		}
+
+		if isSensitive {
+			lines = append(lines, fmt.Sprintf(`      %s = "***"`, k))
+			count++
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("      %s = %s", k, formatAttributeValue(attrs[k])))
+	}
+
+	return lines, count
+}
+
+func formatAttributeValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}